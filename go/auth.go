@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// authenticate wraps a handler so it only runs for requests carrying a
+// valid "Authorization: Bearer <token>" header, injecting the resolved
+// User into the request context.
+func (b *Backend) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" {
+			b.writeJSONError(w, http.StatusUnauthorized, FieldError{Message: "missing or malformed Authorization header"})
+			return
+		}
+
+		b.Lock.Lock()
+		userID, ok := b.Tokens[token]
+		var user User
+		if ok {
+			user, ok = b.Users[userID]
+		}
+		b.Lock.Unlock()
+		if !ok {
+			b.writeJSONError(w, http.StatusUnauthorized, FieldError{Message: "invalid token"})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, user)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func userFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey).(User)
+	return user, ok
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterUser(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	body, _ := json.Marshal(registerUserRequest{Email: "alice@example.com", Password: "hunter2"})
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	backend.RegisterUser(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var resp registerUserResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.ID == "" || resp.Token == "" {
+		t.Errorf("expected non-empty id and token, got %+v", resp)
+	}
+	if _, ok := backend.Tokens[resp.Token]; !ok {
+		t.Errorf("token was not stored on the backend")
+	}
+}
+
+func TestRegisterUser_DuplicateEmail(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	body, _ := json.Marshal(registerUserRequest{Email: "alice@example.com", Password: "hunter2"})
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	backend.RegisterUser(httptest.NewRecorder(), req)
+
+	req2 := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	w2 := httptest.NewRecorder()
+	backend.RegisterUser(w2, req2)
+	if w2.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w2.Code)
+	}
+}
+
+func TestAuthenticate_MissingHeader(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	req := httptest.NewRequest("POST", "/posts", nil)
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.CreatePost)(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAuthenticate_InvalidToken(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	req := httptest.NewRequest("POST", "/posts", nil)
+	req.Header.Set("Authorization", "Bearer does-not-exist")
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.CreatePost)(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAuthenticate_MalformedHeader(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	alice := registerTestUser(backend, "alice")
+	req := httptest.NewRequest("POST", "/posts", nil)
+	req.Header.Set("Authorization", alice.Token)
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.CreatePost)(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -2,7 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
+	"log"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -16,98 +20,201 @@ type Post struct {
 	Content string    `json:"content"`
 }
 
+// PostsPage is the response body of GET /posts.
+type PostsPage struct {
+	Posts      []Post `json:"posts"`
+	NextCursor string `json:"next_cursor"`
+}
+
 type Backend struct {
-	Posts map[string]Post
-	Lock  sync.Mutex
-	Mux   http.ServeMux
+	Store   Store
+	Users   map[string]User
+	Tokens  map[string]string // token -> user ID
+	Limiter *RateLimiter
+	Lock    sync.Mutex
+	Mux     http.ServeMux
+}
+
+// NewServer builds a Backend backed by store, rate-limiting writes to
+// writeLimit requests per window per authenticated user (every write
+// endpoint requires authentication, so there is no unauthenticated case to
+// key by IP), and registers its HTTP handlers.
+func NewServer(store Store, writeLimit int, window time.Duration) *Backend {
+	backend := &Backend{
+		Store:   store,
+		Users:   make(map[string]User),
+		Tokens:  make(map[string]string),
+		Limiter: NewRateLimiter(writeLimit, window),
+		Mux:     *http.NewServeMux(),
+	}
+	backend.RegisterHandlers()
+	return backend
 }
 
 func (b *Backend) GetPosts(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	b.Lock.Lock()
-	defer b.Lock.Unlock()
-	postsList := make([]Post, 0, len(b.Posts))
-	for _, post := range b.Posts {
-		postsList = append(postsList, post)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	opts := ListOptions{
+		Limit:  limit,
+		Cursor: r.URL.Query().Get("cursor"),
+		Author: r.URL.Query().Get("author"),
+	}
+	posts, nextCursor, err := b.Store.List(r.Context(), opts)
+	if errors.Is(err, ErrInvalidCursor) {
+		b.writeJSONError(w, http.StatusBadRequest, FieldError{Field: "cursor", Message: "invalid cursor"})
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(postsList); err != nil {
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to list posts"})
 		return
 	}
+	body, err := json.Marshal(PostsPage{Posts: posts, NextCursor: nextCursor})
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to encode JSON"})
+		return
+	}
+	writeWithETag(w, r, body, http.StatusOK)
 }
 
 func (b *Backend) CreatePost(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		b.writeJSONError(w, http.StatusUnauthorized, FieldError{Message: "unauthorized"})
+		return
+	}
 	var post Post
 	if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
-		http.Error(w, "Failed to decode JSON", http.StatusBadRequest)
+		b.writeJSONError(w, http.StatusBadRequest, FieldError{Message: "failed to decode JSON"})
+		return
+	}
+	if errs := validate(post, true); len(errs) > 0 {
+		b.writeJSONError(w, http.StatusBadRequest, errs...)
 		return
 	}
 	post.ID = uuid.New().String()
-	b.Lock.Lock()
-	defer b.Lock.Unlock()
-	b.Posts[post.ID] = post
+	post.Author = user.ID
+	if err := b.Store.Create(r.Context(), post); err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to create post"})
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(post)
 }
 
 func (b *Backend) GetPostById(w http.ResponseWriter, r *http.Request) {
 	postID := r.PathValue("post_id")
-	b.Lock.Lock()
-	defer b.Lock.Unlock()
-	post, exists := b.Posts[postID]
-	if !exists {
-		http.Error(w, "Post not found", http.StatusNotFound)
+	post, err := b.Store.Get(r.Context(), postID)
+	if errors.Is(err, ErrPostNotFound) {
+		b.writeJSONError(w, http.StatusNotFound, FieldError{Message: "post not found"})
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(post)
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to get post"})
+		return
+	}
+	body, err := json.Marshal(post)
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to encode JSON"})
+		return
+	}
+	writeWithETag(w, r, body, http.StatusOK)
 }
 
 func (b *Backend) UpdatePostById(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		b.writeJSONError(w, http.StatusUnauthorized, FieldError{Message: "unauthorized"})
+		return
+	}
 	postID := r.PathValue("post_id")
 	var updatedPost Post
 	if err := json.NewDecoder(r.Body).Decode(&updatedPost); err != nil {
-		http.Error(w, "Failed to decode JSON", http.StatusBadRequest)
+		b.writeJSONError(w, http.StatusBadRequest, FieldError{Message: "failed to decode JSON"})
+		return
+	}
+	if errs := validate(updatedPost, false); len(errs) > 0 {
+		b.writeJSONError(w, http.StatusBadRequest, errs...)
 		return
 	}
 	updatedPost.ID = postID
-	b.Lock.Lock()
-	defer b.Lock.Unlock()
-	if _, ok := b.Posts[postID]; !ok {
-		http.Error(w, "Post not found", http.StatusNotFound)
+
+	existing, err := b.Store.Get(r.Context(), postID)
+	if errors.Is(err, ErrPostNotFound) {
+		b.writeJSONError(w, http.StatusNotFound, FieldError{Message: "post not found"})
+		return
+	}
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to get post"})
+		return
+	}
+	if existing.Author != user.ID {
+		b.writeJSONError(w, http.StatusForbidden, FieldError{Message: "forbidden"})
+		return
+	}
+	updatedPost.Author = existing.Author
+
+	if err := b.Store.Update(r.Context(), updatedPost); err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to update post"})
 		return
 	}
-	b.Posts[postID] = updatedPost
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updatedPost)
 }
 
 func (b *Backend) DeletePostById(w http.ResponseWriter, r *http.Request) {
+	user, ok := userFromContext(r.Context())
+	if !ok {
+		b.writeJSONError(w, http.StatusUnauthorized, FieldError{Message: "unauthorized"})
+		return
+	}
 	postID := r.PathValue("post_id")
-	b.Lock.Lock()
-	defer b.Lock.Unlock()
-	if _, ok := b.Posts[postID]; !ok {
-		http.Error(w, "Post not found", http.StatusNotFound)
+
+	existing, err := b.Store.Get(r.Context(), postID)
+	if errors.Is(err, ErrPostNotFound) {
+		b.writeJSONError(w, http.StatusNotFound, FieldError{Message: "post not found"})
+		return
+	}
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to get post"})
+		return
+	}
+	if existing.Author != user.ID {
+		b.writeJSONError(w, http.StatusForbidden, FieldError{Message: "forbidden"})
+		return
+	}
+
+	if err := b.Store.Delete(r.Context(), postID); err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to delete post"})
 		return
 	}
-	delete(b.Posts, postID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (b *Backend) RegisterHandlers() {
 	b.Mux.HandleFunc("GET /posts", b.GetPosts)
-	b.Mux.HandleFunc("POST /posts", b.CreatePost)
+	b.Mux.HandleFunc("POST /posts", b.authenticate(b.rateLimit(b.CreatePost)))
 	b.Mux.HandleFunc("GET /posts/{post_id}", b.GetPostById)
-	b.Mux.HandleFunc("PUT /posts/{post_id}", b.UpdatePostById)
-	b.Mux.HandleFunc("DELETE /posts/{post_id}", b.DeletePostById)
+	b.Mux.HandleFunc("PUT /posts/{post_id}", b.authenticate(b.rateLimit(b.UpdatePostById)))
+	b.Mux.HandleFunc("DELETE /posts/{post_id}", b.authenticate(b.rateLimit(b.DeletePostById)))
+	b.Mux.HandleFunc("POST /users", b.RegisterUser)
 }
 
 func main() {
-	backend := Backend{
-		Posts: make(map[string]Post),
-		Mux:   *http.NewServeMux(),
+	dbPath := flag.String("db", "", "path to a SQLite database file; when empty, posts are kept in memory only")
+	writeLimit := flag.Int("write-limit", 20, "number of writes allowed per caller within -write-window")
+	writeWindow := flag.Duration("write-window", 5*time.Minute, "sliding window over which -write-limit applies")
+	flag.Parse()
+
+	var store Store
+	if *dbPath != "" {
+		sqliteStore, err := NewSQLiteStore(*dbPath)
+		if err != nil {
+			log.Fatalf("failed to open SQLite store: %v", err)
+		}
+		store = sqliteStore
+	} else {
+		store = NewMemoryStore()
 	}
-	backend.RegisterHandlers()
+
+	backend := NewServer(store, *writeLimit, *writeWindow)
 	http.ListenAndServe(":8080", &backend.Mux)
 }
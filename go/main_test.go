@@ -2,191 +2,275 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 	"time"
 )
 
-func setupBackend() *Backend {
+func setupBackend(store Store) *Backend {
 	backend := &Backend{
-		Posts: make(map[string]Post),
-		Mux:   http.ServeMux{},
+		Store:   store,
+		Users:   make(map[string]User),
+		Tokens:  make(map[string]string),
+		Limiter: NewRateLimiter(1000, time.Hour),
+		Mux:     http.ServeMux{},
 	}
 	backend.RegisterHandlers()
 	return backend
 }
 
-func TestCreatePost(t *testing.T) {
-	backend := setupBackend()
-	post := Post{Author: "Alice", Date: time.Now(), Content: "Hello"}
-	body, _ := json.Marshal(post)
-	req := httptest.NewRequest("POST", "/posts", bytes.NewReader(body))
-	w := httptest.NewRecorder()
-	backend.CreatePost(w, req)
-	if w.Code != http.StatusCreated {
-		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
-	}
-	var created Post
-	json.NewDecoder(w.Body).Decode(&created)
-	if created.Author != post.Author || created.Content != post.Content {
-		t.Errorf("unexpected post data: %+v", created)
-	}
+// registerTestUser inserts a user directly into the backend and returns it,
+// so handler tests can authenticate without going through RegisterUser.
+func registerTestUser(backend *Backend, id string) User {
+	user := User{ID: id, Email: id + "@example.com", Token: id + "-token"}
+	backend.Users[user.ID] = user
+	backend.Tokens[user.Token] = user.ID
+	return user
 }
 
-func TestGetPosts(t *testing.T) {
-	backend := setupBackend()
-	// Add a post
-	backend.Posts["1"] = Post{ID: "1", Author: "Bob", Date: time.Now(), Content: "Hi"}
-	req := httptest.NewRequest("GET", "/posts", nil)
-	w := httptest.NewRecorder()
-	backend.GetPosts(w, req)
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
-	}
-	var posts []Post
-	json.NewDecoder(w.Body).Decode(&posts)
-	if len(posts) != 1 || posts[0].Author != "Bob" {
-		t.Errorf("unexpected posts: %+v", posts)
+func authedRequest(method, target string, body []byte, user User) *http.Request {
+	var req *http.Request
+	if body == nil {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, bytes.NewReader(body))
 	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+	return req
 }
 
-func TestGetPostById(t *testing.T) {
-	backend := setupBackend()
-	backend.Posts["42"] = Post{ID: "42", Author: "Carol", Date: time.Now(), Content: "Test"}
-	req := httptest.NewRequest("GET", "/posts/42", nil)
-	req.SetPathValue("post_id", "42")
-	w := httptest.NewRecorder()
-	backend.GetPostById(w, req)
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
-	}
-	var post Post
-	json.NewDecoder(w.Body).Decode(&post)
-	if post.ID != "42" || post.Author != "Carol" {
-		t.Errorf("unexpected post: %+v", post)
-	}
-}
+// runBackendTests exercises the Post handlers against whatever Store
+// newStore produces, so the suite runs unchanged against every Store
+// implementation.
+func runBackendTests(t *testing.T, newStore func(t *testing.T) Store) {
+	t.Run("CreatePost", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		alice := registerTestUser(backend, "alice")
+		post := Post{Author: "Alice", Date: time.Now(), Content: "Hello"}
+		body, _ := json.Marshal(post)
+		req := authedRequest("POST", "/posts", body, alice)
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.CreatePost)(w, req)
+		if w.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		var created Post
+		json.NewDecoder(w.Body).Decode(&created)
+		if created.Author != alice.ID || created.Content != post.Content {
+			t.Errorf("unexpected post data: %+v", created)
+		}
+	})
 
-func TestUpdatePostById(t *testing.T) {
-	backend := setupBackend()
-	backend.Posts["99"] = Post{ID: "99", Author: "Dan", Date: time.Now(), Content: "Old"}
-	updated := Post{Author: "Dan", Date: time.Now(), Content: "New"}
-	body, _ := json.Marshal(updated)
-	req := httptest.NewRequest("PUT", "/posts/99", bytes.NewReader(body))
-	req.SetPathValue("post_id", "99")
-	w := httptest.NewRecorder()
-	backend.UpdatePostById(w, req)
-	if w.Code != http.StatusOK {
-		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
-	}
-	var post Post
-	json.NewDecoder(w.Body).Decode(&post)
-	if post.Content != "New" {
-		t.Errorf("expected updated content, got %+v", post)
-	}
-}
+	t.Run("GetPosts", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		backend.Store.Create(context.Background(), Post{ID: "1", Author: "Bob", Date: time.Now(), Content: "Hi"})
+		req := httptest.NewRequest("GET", "/posts", nil)
+		w := httptest.NewRecorder()
+		backend.GetPosts(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var page PostsPage
+		json.NewDecoder(w.Body).Decode(&page)
+		if len(page.Posts) != 1 || page.Posts[0].Author != "Bob" {
+			t.Errorf("unexpected posts page: %+v", page)
+		}
+	})
 
-func TestDeletePostById(t *testing.T) {
-	backend := setupBackend()
-	backend.Posts["7"] = Post{ID: "7", Author: "Eve", Date: time.Now(), Content: "Bye"}
-	req := httptest.NewRequest("DELETE", "/posts/7", nil)
-	req.SetPathValue("post_id", "7")
-	w := httptest.NewRecorder()
-	backend.DeletePostById(w, req)
-	if w.Code != http.StatusNoContent {
-		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
-	}
-	if _, exists := backend.Posts["7"]; exists {
-		t.Errorf("post was not deleted")
-	}
-}
+	t.Run("GetPostById", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		backend.Store.Create(context.Background(), Post{ID: "42", Author: "Carol", Date: time.Now(), Content: "Test"})
+		req := httptest.NewRequest("GET", "/posts/42", nil)
+		req.SetPathValue("post_id", "42")
+		w := httptest.NewRecorder()
+		backend.GetPostById(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var post Post
+		json.NewDecoder(w.Body).Decode(&post)
+		if post.ID != "42" || post.Author != "Carol" {
+			t.Errorf("unexpected post: %+v", post)
+		}
+	})
 
-func TestGetPostById_NotFound(t *testing.T) {
-	backend := setupBackend()
-	req := httptest.NewRequest("GET", "/posts/404", nil)
-	req.SetPathValue("post_id", "404")
-	w := httptest.NewRecorder()
-	backend.GetPostById(w, req)
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
-	}
-}
+	t.Run("UpdatePostById", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		dan := registerTestUser(backend, "dan")
+		backend.Store.Create(context.Background(), Post{ID: "99", Author: dan.ID, Date: time.Now(), Content: "Old"})
+		updated := Post{Author: dan.ID, Date: time.Now(), Content: "New"}
+		body, _ := json.Marshal(updated)
+		req := authedRequest("PUT", "/posts/99", body, dan)
+		req.SetPathValue("post_id", "99")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.UpdatePostById)(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var post Post
+		json.NewDecoder(w.Body).Decode(&post)
+		if post.Content != "New" {
+			t.Errorf("expected updated content, got %+v", post)
+		}
+	})
 
-func TestUpdatePostById_NotFound(t *testing.T) {
-	backend := setupBackend()
-	updated := Post{Author: "Ghost", Date: time.Now(), Content: "Nothing"}
-	body, _ := json.Marshal(updated)
-	req := httptest.NewRequest("PUT", "/posts/404", bytes.NewReader(body))
-	req.SetPathValue("post_id", "404")
-	w := httptest.NewRecorder()
-	backend.UpdatePostById(w, req)
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
-	}
-}
+	t.Run("UpdatePostById_WrongAuthor", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		dan := registerTestUser(backend, "dan")
+		mallory := registerTestUser(backend, "mallory")
+		backend.Store.Create(context.Background(), Post{ID: "99", Author: dan.ID, Date: time.Now(), Content: "Old"})
+		updated := Post{Author: dan.ID, Date: time.Now(), Content: "Hijacked"}
+		body, _ := json.Marshal(updated)
+		req := authedRequest("PUT", "/posts/99", body, mallory)
+		req.SetPathValue("post_id", "99")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.UpdatePostById)(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+		}
+		stored, _ := backend.Store.Get(context.Background(), "99")
+		if stored.Content != "Old" {
+			t.Errorf("post should not have been modified by a different author")
+		}
+	})
 
-func TestDeletePostById_NotFound(t *testing.T) {
-	backend := setupBackend()
-	req := httptest.NewRequest("DELETE", "/posts/404", nil)
-	req.SetPathValue("post_id", "404")
-	w := httptest.NewRecorder()
-	backend.DeletePostById(w, req)
-	if w.Code != http.StatusNotFound {
-		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
-	}
-}
+	t.Run("DeletePostById", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		eve := registerTestUser(backend, "eve")
+		backend.Store.Create(context.Background(), Post{ID: "7", Author: eve.ID, Date: time.Now(), Content: "Bye"})
+		req := authedRequest("DELETE", "/posts/7", nil, eve)
+		req.SetPathValue("post_id", "7")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.DeletePostById)(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+		}
+		if _, err := backend.Store.Get(context.Background(), "7"); err != ErrPostNotFound {
+			t.Errorf("post was not deleted")
+		}
+	})
 
-func TestCreatePost_InvalidJSON(t *testing.T) {
-	backend := setupBackend()
-	body := []byte("{invalid json}")
-	req := httptest.NewRequest("POST", "/posts", bytes.NewReader(body))
-	w := httptest.NewRecorder()
-	backend.CreatePost(w, req)
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
-	}
-}
+	t.Run("DeletePostById_WrongAuthor", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		eve := registerTestUser(backend, "eve")
+		mallory := registerTestUser(backend, "mallory")
+		backend.Store.Create(context.Background(), Post{ID: "7", Author: eve.ID, Date: time.Now(), Content: "Bye"})
+		req := authedRequest("DELETE", "/posts/7", nil, mallory)
+		req.SetPathValue("post_id", "7")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.DeletePostById)(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+		}
+		if _, err := backend.Store.Get(context.Background(), "7"); err != nil {
+			t.Errorf("post should not have been deleted by a different author")
+		}
+	})
 
-func TestUpdatePostById_InvalidJSON(t *testing.T) {
-	backend := setupBackend()
-	body := []byte("{invalid json}")
-	req := httptest.NewRequest("PUT", "/posts/1", bytes.NewReader(body))
-	req.SetPathValue("post_id", "1")
-	w := httptest.NewRecorder()
-	backend.UpdatePostById(w, req)
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
-	}
-}
+	t.Run("GetPostById_NotFound", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		req := httptest.NewRequest("GET", "/posts/404", nil)
+		req.SetPathValue("post_id", "404")
+		w := httptest.NewRecorder()
+		backend.GetPostById(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("UpdatePostById_NotFound", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		ghost := registerTestUser(backend, "ghost")
+		updated := Post{Author: ghost.ID, Date: time.Now(), Content: "Nothing"}
+		body, _ := json.Marshal(updated)
+		req := authedRequest("PUT", "/posts/404", body, ghost)
+		req.SetPathValue("post_id", "404")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.UpdatePostById)(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("DeletePostById_NotFound", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		ghost := registerTestUser(backend, "ghost")
+		req := authedRequest("DELETE", "/posts/404", nil, ghost)
+		req.SetPathValue("post_id", "404")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.DeletePostById)(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
 
-// Test for JSON encoding error in GetPosts
+	t.Run("CreatePost_InvalidJSON", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		alice := registerTestUser(backend, "alice")
+		body := []byte("{invalid json}")
+		req := authedRequest("POST", "/posts", body, alice)
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.CreatePost)(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("UpdatePostById_InvalidJSON", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		alice := registerTestUser(backend, "alice")
+		backend.Store.Create(context.Background(), Post{ID: "1", Author: alice.ID, Date: time.Now(), Content: "Old"})
+		body := []byte("{invalid json}")
+		req := authedRequest("PUT", "/posts/1", body, alice)
+		req.SetPathValue("post_id", "1")
+		w := httptest.NewRecorder()
+		backend.authenticate(backend.UpdatePostById)(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
 
-// errorWriter simulates a ResponseWriter that always fails to write
-type errorWriter struct {
-	http.ResponseWriter
 }
 
-func (ew *errorWriter) Write(p []byte) (int, error) {
-	return 0, fmt.Errorf("forced error")
+func TestBackend_MemoryStore(t *testing.T) {
+	runBackendTests(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
 }
 
-type BadPost struct{}
+func TestBackend_SQLiteStore(t *testing.T) {
+	runBackendTests(t, func(t *testing.T) Store {
+		return mustSQLiteStore(t)
+	})
+}
 
-func (b BadPost) MarshalJSON() ([]byte, error) {
-	return nil, fmt.Errorf("forced error")
+// mustSQLiteStore opens a SQLiteStore backed by a temp-dir database file,
+// closing it automatically at the end of the test.
+func mustSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open SQLite store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
 }
 
+// TestGetPosts_EncodingError exercises the json.Marshal failure branch of
+// GetPosts. time.Time.MarshalJSON rejects years outside [0,9999], which is
+// the only reachable way to make a Post fail to encode; it's MemoryStore-only
+// because SQLite can't round-trip a date that far out of range.
 func TestGetPosts_EncodingError(t *testing.T) {
-	backend := setupBackend()
-	backend.Posts["1"] = Post{ID: "1", Author: "Bob", Date: time.Now(), Content: "Hi"}
-	rr := httptest.NewRecorder()
-	ew := &errorWriter{rr}
+	backend := setupBackend(NewMemoryStore())
+	backend.Store.Create(context.Background(), Post{ID: "1", Author: "Bob", Date: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), Content: "Hi"})
 	req := httptest.NewRequest("GET", "/posts", nil)
-	backend.GetPosts(ew, req)
-	if rr.Code != http.StatusInternalServerError {
-		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	w := httptest.NewRecorder()
+	backend.GetPosts(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
 	}
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store implementation, primarily used in tests.
+type MemoryStore struct {
+	mu    sync.Mutex
+	posts map[string]Post
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{posts: make(map[string]Post)}
+}
+
+func (s *MemoryStore) List(ctx context.Context, opts ListOptions) ([]Post, string, error) {
+	var cursor cursorPayload
+	if opts.Cursor != "" {
+		decoded, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		cursor = decoded
+	}
+	limit := normalizeLimit(opts.Limit)
+
+	s.mu.Lock()
+	matching := make([]Post, 0, len(s.posts))
+	for _, post := range s.posts {
+		if opts.Author != "" && post.Author != opts.Author {
+			continue
+		}
+		if opts.Cursor != "" && !afterCursor(post, cursor) {
+			continue
+		}
+		matching = append(matching, post)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(matching, func(i, j int) bool {
+		if !matching[i].Date.Equal(matching[j].Date) {
+			return matching[i].Date.After(matching[j].Date)
+		}
+		return matching[i].ID < matching[j].ID
+	})
+
+	if len(matching) > limit {
+		return matching[:limit], encodeCursor(matching[limit-1]), nil
+	}
+	return matching, "", nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	post, ok := s.posts[id]
+	if !ok {
+		return Post{}, ErrPostNotFound
+	}
+	return post, nil
+}
+
+func (s *MemoryStore) Create(ctx context.Context, post Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.posts[post.ID] = post
+	return nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, post Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.posts[post.ID]; !ok {
+		return ErrPostNotFound
+	}
+	s.posts[post.ID] = post
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.posts[id]; !ok {
+		return ErrPostNotFound
+	}
+	delete(s.posts, id)
+	return nil
+}
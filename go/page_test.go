@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func seedPosts(t *testing.T, backend *Backend, n int, author string) []Post {
+	t.Helper()
+	posts := make([]Post, n)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		post := Post{
+			ID:      fmt.Sprintf("%s-%03d", author, i),
+			Author:  author,
+			Date:    base.Add(time.Duration(i) * time.Minute),
+			Content: fmt.Sprintf("post %d", i),
+		}
+		if err := backend.Store.Create(context.Background(), post); err != nil {
+			t.Fatalf("failed to seed post: %v", err)
+		}
+		posts[i] = post
+	}
+	return posts
+}
+
+func runPaginationTests(t *testing.T, newStore func(t *testing.T) Store) {
+	t.Run("LimitAndNextCursor", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		seedPosts(t, backend, 5, "alice")
+
+		req := httptest.NewRequest("GET", "/posts?limit=2", nil)
+		w := httptest.NewRecorder()
+		backend.GetPosts(w, req)
+		if w.Code != 200 {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var page PostsPage
+		json.NewDecoder(w.Body).Decode(&page)
+		if len(page.Posts) != 2 {
+			t.Fatalf("expected 2 posts, got %d", len(page.Posts))
+		}
+		// Newest first: post 4 then post 3.
+		if page.Posts[0].ID != "alice-004" || page.Posts[1].ID != "alice-003" {
+			t.Errorf("unexpected order: %+v", page.Posts)
+		}
+		if page.NextCursor == "" {
+			t.Fatalf("expected a next_cursor since more posts remain")
+		}
+
+		req2 := httptest.NewRequest("GET", "/posts?limit=2&cursor="+page.NextCursor, nil)
+		w2 := httptest.NewRecorder()
+		backend.GetPosts(w2, req2)
+		var page2 PostsPage
+		json.NewDecoder(w2.Body).Decode(&page2)
+		if len(page2.Posts) != 2 || page2.Posts[0].ID != "alice-002" || page2.Posts[1].ID != "alice-001" {
+			t.Errorf("unexpected second page: %+v", page2.Posts)
+		}
+	})
+
+	t.Run("CursorStableAcrossInserts", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		seedPosts(t, backend, 3, "alice")
+
+		req := httptest.NewRequest("GET", "/posts?limit=2", nil)
+		w := httptest.NewRecorder()
+		backend.GetPosts(w, req)
+		var page PostsPage
+		json.NewDecoder(w.Body).Decode(&page)
+		cursor := page.NextCursor
+
+		// Insert a new, newer post after the first page was fetched.
+		backend.Store.Create(context.Background(), Post{
+			ID:      "alice-new",
+			Author:  "alice",
+			Date:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			Content: "inserted later",
+		})
+
+		req2 := httptest.NewRequest("GET", "/posts?limit=2&cursor="+cursor, nil)
+		w2 := httptest.NewRecorder()
+		backend.GetPosts(w2, req2)
+		var page2 PostsPage
+		json.NewDecoder(w2.Body).Decode(&page2)
+		if len(page2.Posts) != 1 || page2.Posts[0].ID != "alice-000" {
+			t.Errorf("inserting a newer post should not have shifted the existing cursor's page, got %+v", page2.Posts)
+		}
+	})
+
+	t.Run("FilterByAuthor", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		seedPosts(t, backend, 2, "alice")
+		seedPosts(t, backend, 2, "bob")
+
+		req := httptest.NewRequest("GET", "/posts?author=bob", nil)
+		w := httptest.NewRecorder()
+		backend.GetPosts(w, req)
+		var page PostsPage
+		json.NewDecoder(w.Body).Decode(&page)
+		if len(page.Posts) != 2 {
+			t.Fatalf("expected 2 posts from bob, got %d", len(page.Posts))
+		}
+		for _, post := range page.Posts {
+			if post.Author != "bob" {
+				t.Errorf("unexpected author in filtered results: %+v", post)
+			}
+		}
+	})
+
+	t.Run("GetPosts_NotModified", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		seedPosts(t, backend, 1, "alice")
+
+		req := httptest.NewRequest("GET", "/posts", nil)
+		w := httptest.NewRecorder()
+		backend.GetPosts(w, req)
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("expected an ETag header")
+		}
+
+		req2 := httptest.NewRequest("GET", "/posts", nil)
+		req2.Header.Set("If-None-Match", etag)
+		w2 := httptest.NewRecorder()
+		backend.GetPosts(w2, req2)
+		if w2.Code != 304 {
+			t.Errorf("expected 304, got %d", w2.Code)
+		}
+		if w2.Body.Len() != 0 {
+			t.Errorf("expected an empty body on 304, got %q", w2.Body.String())
+		}
+	})
+
+	t.Run("GetPostById_NotModified", func(t *testing.T) {
+		backend := setupBackend(newStore(t))
+		posts := seedPosts(t, backend, 1, "alice")
+
+		req := httptest.NewRequest("GET", "/posts/"+posts[0].ID, nil)
+		req.SetPathValue("post_id", posts[0].ID)
+		w := httptest.NewRecorder()
+		backend.GetPostById(w, req)
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("expected an ETag header")
+		}
+
+		req2 := httptest.NewRequest("GET", "/posts/"+posts[0].ID, nil)
+		req2.SetPathValue("post_id", posts[0].ID)
+		req2.Header.Set("If-None-Match", etag)
+		w2 := httptest.NewRecorder()
+		backend.GetPostById(w2, req2)
+		if w2.Code != 304 {
+			t.Errorf("expected 304, got %d", w2.Code)
+		}
+	})
+}
+
+func TestPagination_MemoryStore(t *testing.T) {
+	runPaginationTests(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
+}
+
+func TestPagination_SQLiteStore(t *testing.T) {
+	runPaginationTests(t, func(t *testing.T) Store {
+		return mustSQLiteStore(t)
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ErrInvalidCursor is returned when a client-supplied cursor can't be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListOptions controls a paginated, filtered post listing. Posts are always
+// ordered by Date descending, with ID ascending as a tiebreaker so the order
+// is stable across inserts.
+type ListOptions struct {
+	Limit  int
+	Cursor string // opaque, from a previous response's next_cursor
+	Author string // optional, exact match
+}
+
+type cursorPayload struct {
+	ID   string    `json:"id"`
+	Date time.Time `json:"date"`
+}
+
+func encodeCursor(post Post) string {
+	payload, _ := json.Marshal(cursorPayload{ID: post.ID, Date: post.Date})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeCursor(cursor string) (cursorPayload, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return cursorPayload{}, ErrInvalidCursor
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return cursorPayload{}, ErrInvalidCursor
+	}
+	return payload, nil
+}
+
+// afterCursor reports whether post comes strictly after the position marked
+// by cursor in the (Date desc, ID asc) ordering.
+func afterCursor(post Post, cursor cursorPayload) bool {
+	if post.Date.Before(cursor.Date) {
+		return true
+	}
+	return post.Date.Equal(cursor.Date) && post.ID > cursor.ID
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
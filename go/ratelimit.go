@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter enforces a fixed number of events per key within a sliding
+// window, keeping only the last `limit` timestamps per key (a bounded ring)
+// so memory use doesn't grow with request volume.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu    sync.Mutex
+	rings map[string]*ring
+}
+
+type ring struct {
+	timestamps []time.Time
+	next       int
+	filled     bool
+}
+
+// NewRateLimiter builds a RateLimiter permitting limit events per window for
+// each key. A limit below 1 denies every event, rather than panicking on the
+// zero-length ring that would otherwise result.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		rings:  make(map[string]*ring),
+	}
+}
+
+// Allow reports whether an event for key is permitted now. When it is not,
+// it also returns how long the caller should wait before retrying.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	if rl.limit < 1 {
+		return false, rl.window
+	}
+
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	r, ok := rl.rings[key]
+	if !ok {
+		r = &ring{timestamps: make([]time.Time, rl.limit)}
+		rl.rings[key] = r
+	}
+
+	if !r.filled {
+		r.timestamps[r.next] = now
+		r.next = (r.next + 1) % rl.limit
+		if r.next == 0 {
+			r.filled = true
+		}
+		return true, 0
+	}
+
+	oldest := r.timestamps[r.next]
+	if age := now.Sub(oldest); age < rl.window {
+		return false, rl.window - age
+	}
+
+	r.timestamps[r.next] = now
+	r.next = (r.next + 1) % rl.limit
+	return true, 0
+}
+
+// rateLimitKey identifies the caller for rate limiting by their authenticated
+// user ID. Every write endpoint wraps rateLimit inside authenticate, so a
+// user is always present in the request context by the time this runs.
+func rateLimitKey(r *http.Request) string {
+	user, _ := userFromContext(r.Context())
+	return "user:" + user.ID
+}
+
+// rateLimit wraps a write handler so it returns 429 Too Many Requests, with
+// a Retry-After header, once the caller exceeds the configured sliding
+// window.
+func (b *Backend) rateLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed, retryAfter := b.Limiter.Allow(rateLimitKey(r))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			b.writeJSONError(w, http.StatusTooManyRequests, FieldError{Message: fmt.Sprintf("too many requests, retry in %s", retryAfter.Round(time.Second))})
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AllowsBurstUpToLimit(t *testing.T) {
+	rl := NewRateLimiter(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		if allowed, _ := rl.Allow("alice"); !allowed {
+			t.Fatalf("request %d should have been allowed", i)
+		}
+	}
+	if allowed, retryAfter := rl.Allow("alice"); allowed {
+		t.Errorf("4th request within the window should have been denied")
+	} else if retryAfter <= 0 {
+		t.Errorf("expected a positive retry-after, got %s", retryAfter)
+	}
+}
+
+func TestRateLimiter_WindowSlidesAsTimestampsExpire(t *testing.T) {
+	rl := NewRateLimiter(2, 10*time.Millisecond)
+	if allowed, _ := rl.Allow("alice"); !allowed {
+		t.Fatal("1st request should have been allowed")
+	}
+	if allowed, _ := rl.Allow("alice"); !allowed {
+		t.Fatal("2nd request should have been allowed")
+	}
+	if allowed, _ := rl.Allow("alice"); allowed {
+		t.Fatal("3rd request should have been denied while the window is full")
+	}
+	time.Sleep(15 * time.Millisecond)
+	if allowed, _ := rl.Allow("alice"); !allowed {
+		t.Errorf("request after the window elapsed should have been allowed")
+	}
+}
+
+func TestRateLimiter_KeysAreIndependent(t *testing.T) {
+	rl := NewRateLimiter(1, time.Minute)
+	if allowed, _ := rl.Allow("alice"); !allowed {
+		t.Fatal("alice's first request should have been allowed")
+	}
+	if allowed, _ := rl.Allow("alice"); allowed {
+		t.Fatal("alice's second request should have been denied")
+	}
+	if allowed, _ := rl.Allow("bob"); !allowed {
+		t.Errorf("bob should not be limited by alice's usage")
+	}
+}
+
+func TestBackend_RateLimitsWriteEndpoint(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	backend.Limiter = NewRateLimiter(1, time.Minute)
+	alice := registerTestUser(backend, "alice")
+
+	post := Post{Author: "Alice", Date: time.Now(), Content: "Hello"}
+	body, _ := json.Marshal(post)
+
+	req1 := authedRequest("POST", "/posts", body, alice)
+	w1 := httptest.NewRecorder()
+	backend.authenticate(backend.rateLimit(backend.CreatePost))(w1, req1)
+	if w1.Code != 201 {
+		t.Fatalf("expected first request to succeed, got %d", w1.Code)
+	}
+
+	req2 := authedRequest("POST", "/posts", body, alice)
+	w2 := httptest.NewRecorder()
+	backend.authenticate(backend.rateLimit(backend.CreatePost))(w2, req2)
+	if w2.Code != 429 {
+		t.Fatalf("expected second request to be rate limited, got %d", w2.Code)
+	}
+	if w2.Header().Get("Retry-After") == "" {
+		t.Errorf("expected a Retry-After header on a 429 response")
+	}
+}
+
+func TestBackend_RateLimitIndependentPerUser(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	backend.Limiter = NewRateLimiter(1, time.Minute)
+	alice := registerTestUser(backend, "alice")
+	bob := registerTestUser(backend, "bob")
+
+	post := Post{Author: "Alice", Date: time.Now(), Content: "Hello"}
+	body, _ := json.Marshal(post)
+
+	w1 := httptest.NewRecorder()
+	backend.authenticate(backend.rateLimit(backend.CreatePost))(w1, authedRequest("POST", "/posts", body, alice))
+	if w1.Code != 201 {
+		t.Fatalf("expected alice's request to succeed, got %d", w1.Code)
+	}
+
+	w2 := httptest.NewRecorder()
+	backend.authenticate(backend.rateLimit(backend.CreatePost))(w2, authedRequest("POST", "/posts", body, bob))
+	if w2.Code != 201 {
+		t.Errorf("expected bob's request to succeed despite alice's limit, got %d", w2.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed sql/init.sql
+var sqliteSchema string
+
+// SQLiteStore is a Store backed by a SQLite database, so posts survive
+// process restarts. Get/Create/Update/Delete have a fixed query shape, so
+// their statements are prepared once at construction; List's query is built
+// per call since it varies with which of opts.Author/opts.Cursor are set.
+type SQLiteStore struct {
+	db *sql.DB
+
+	getStmt    *sql.Stmt
+	createStmt *sql.Stmt
+	updateStmt *sql.Stmt
+	deleteStmt *sql.Stmt
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at path,
+// applies the schema in sql/init.sql, and prepares the fixed-shape queries.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &SQLiteStore{db: db}
+	stmts := []struct {
+		dst   **sql.Stmt
+		query string
+	}{
+		{&s.getStmt, "SELECT id, author, date, content FROM posts WHERE id = ?"},
+		{&s.createStmt, "INSERT INTO posts (id, author, date, content) VALUES (?, ?, ?, ?)"},
+		{&s.updateStmt, "UPDATE posts SET author = ?, date = ?, content = ? WHERE id = ?"},
+		{&s.deleteStmt, "DELETE FROM posts WHERE id = ?"},
+	}
+	for _, stmt := range stmts {
+		prepared, err := db.Prepare(stmt.query)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		*stmt.dst = prepared
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	s.getStmt.Close()
+	s.createStmt.Close()
+	s.updateStmt.Close()
+	s.deleteStmt.Close()
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) List(ctx context.Context, opts ListOptions) ([]Post, string, error) {
+	var cursor cursorPayload
+	if opts.Cursor != "" {
+		decoded, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		cursor = decoded
+	}
+	limit := normalizeLimit(opts.Limit)
+
+	query := "SELECT id, author, date, content FROM posts WHERE 1 = 1"
+	args := make([]any, 0, 4)
+	if opts.Author != "" {
+		query += " AND author = ?"
+		args = append(args, opts.Author)
+	}
+	if opts.Cursor != "" {
+		query += " AND (date < ? OR (date = ? AND id > ?))"
+		args = append(args, cursor.Date, cursor.Date, cursor.ID)
+	}
+	query += " ORDER BY date DESC, id ASC LIMIT ?"
+	args = append(args, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	posts := make([]Post, 0, limit+1)
+	for rows.Next() {
+		var post Post
+		if err := rows.Scan(&post.ID, &post.Author, &post.Date, &post.Content); err != nil {
+			return nil, "", err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(posts) > limit {
+		return posts[:limit], encodeCursor(posts[limit-1]), nil
+	}
+	return posts, "", nil
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, id string) (Post, error) {
+	row := s.getStmt.QueryRowContext(ctx, id)
+	var post Post
+	if err := row.Scan(&post.ID, &post.Author, &post.Date, &post.Content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Post{}, ErrPostNotFound
+		}
+		return Post{}, err
+	}
+	return post, nil
+}
+
+func (s *SQLiteStore) Create(ctx context.Context, post Post) error {
+	_, err := s.createStmt.ExecContext(ctx, post.ID, post.Author, post.Date, post.Content)
+	return err
+}
+
+func (s *SQLiteStore) Update(ctx context.Context, post Post) error {
+	result, err := s.updateStmt.ExecContext(ctx, post.Author, post.Date, post.Content, post.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	result, err := s.deleteStmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
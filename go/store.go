@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPostNotFound is returned by a Store when no post exists for the given ID.
+var ErrPostNotFound = errors.New("post not found")
+
+// Store abstracts post persistence so Backend can run against an in-memory
+// map (tests) or a durable backend such as SQLite.
+type Store interface {
+	// List returns posts ordered by Date descending (ID ascending breaks
+	// ties), honoring opts.Limit/Cursor/Author. It also returns the cursor
+	// for the next page, or "" when the listing is exhausted.
+	List(ctx context.Context, opts ListOptions) (posts []Post, nextCursor string, err error)
+	Get(ctx context.Context, id string) (Post, error)
+	Create(ctx context.Context, post Post) error
+	Update(ctx context.Context, post Post) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type User struct {
+	ID           string `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash []byte `json:"-"`
+	Token        string `json:"token"`
+}
+
+type registerUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type registerUserResponse struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+func generateToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func (b *Backend) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	var req registerUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		b.writeJSONError(w, http.StatusBadRequest, FieldError{Message: "failed to decode JSON"})
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		b.writeJSONError(w, http.StatusBadRequest, FieldError{Message: "email and password are required"})
+		return
+	}
+
+	b.Lock.Lock()
+	defer b.Lock.Unlock()
+
+	for _, existing := range b.Users {
+		if existing.Email == req.Email {
+			b.writeJSONError(w, http.StatusConflict, FieldError{Field: "email", Message: "already registered"})
+			return
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to hash password"})
+		return
+	}
+	token, err := generateToken()
+	if err != nil {
+		b.writeJSONError(w, http.StatusInternalServerError, FieldError{Message: "failed to generate token"})
+		return
+	}
+
+	user := User{
+		ID:           uuid.New().String(),
+		Email:        req.Email,
+		PasswordHash: hash,
+		Token:        token,
+	}
+	b.Users[user.ID] = user
+	b.Tokens[user.Token] = user.ID
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(registerUserResponse{ID: user.ID, Token: user.Token})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxContentBytes = 10 * 1024 // 10 KB
+
+// FieldError is a single validation failure, or a general API error when
+// Field is empty.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// writeJSONError replies with status and a {"errors": [...]} body, used in
+// place of http.Error everywhere in this package so clients always get a
+// consistent, structured error shape.
+func (b *Backend) writeJSONError(w http.ResponseWriter, status int, errs ...FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Errors []FieldError `json:"errors"`
+	}{Errors: errs})
+}
+
+// validate checks a decoded Post payload before it is persisted. isCreate
+// enables the rules that only apply when creating a post: the client must
+// not supply its own ID, and must supply a non-empty Author (the server
+// still overrides it with the authenticated user's ID, but the field is
+// required for backwards-compatible clients).
+func validate(p Post, isCreate bool) []FieldError {
+	var errs []FieldError
+
+	if isCreate && p.ID != "" {
+		errs = append(errs, FieldError{Field: "id", Message: "must not be set by the client"})
+	}
+	if isCreate && strings.TrimSpace(p.Author) == "" {
+		errs = append(errs, FieldError{Field: "author", Message: "must not be empty"})
+	}
+	if strings.TrimSpace(p.Content) == "" {
+		errs = append(errs, FieldError{Field: "content", Message: "must not be empty"})
+	} else if len(p.Content) > maxContentBytes {
+		errs = append(errs, FieldError{Field: "content", Message: "must be at most 10KB"})
+	}
+	if p.Date.After(time.Now()) {
+		errs = append(errs, FieldError{Field: "date", Message: "must not be in the future"})
+	}
+
+	return errs
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	tooLong := strings.Repeat("a", maxContentBytes+1)
+
+	tests := []struct {
+		name     string
+		post     Post
+		isCreate bool
+		wantErr  string // field of the expected error, "" means no error
+	}{
+		{"valid create", Post{Author: "alice", Content: "hello", Date: time.Now()}, true, ""},
+		{"valid update", Post{ID: "1", Author: "alice", Content: "hello", Date: time.Now()}, false, ""},
+		{"empty content", Post{Author: "alice", Content: "", Date: time.Now()}, true, "content"},
+		{"blank content", Post{Author: "alice", Content: "   ", Date: time.Now()}, true, "content"},
+		{"content too long", Post{Author: "alice", Content: tooLong, Date: time.Now()}, true, "content"},
+		{"empty author on create", Post{Author: "", Content: "hello", Date: time.Now()}, true, "author"},
+		{"empty author on update is fine", Post{Author: "", Content: "hello", Date: time.Now()}, false, ""},
+		{"future date", Post{Author: "alice", Content: "hello", Date: future}, true, "date"},
+		{"client-supplied id on create", Post{ID: "not-allowed", Author: "alice", Content: "hello", Date: time.Now()}, true, "id"},
+		{"client-supplied id on update is fine", Post{ID: "1", Author: "alice", Content: "hello", Date: time.Now()}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validate(tt.post, tt.isCreate)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Fatalf("expected no errors, got %+v", errs)
+				}
+				return
+			}
+			found := false
+			for _, e := range errs {
+				if e.Field == tt.wantErr {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("expected an error on field %q, got %+v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestWriteJSONError_Shape(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	w := httptest.NewRecorder()
+	backend.writeJSONError(w, 400, FieldError{Field: "content", Message: "must not be empty"})
+
+	if w.Code != 400 {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", ct)
+	}
+
+	var body struct {
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode error body: %v", err)
+	}
+	if len(body.Errors) != 1 || body.Errors[0].Field != "content" || body.Errors[0].Message != "must not be empty" {
+		t.Errorf("unexpected error body: %+v", body.Errors)
+	}
+}
+
+func TestCreatePost_ValidationError(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	alice := registerTestUser(backend, "alice")
+	post := Post{Author: "alice", Content: "", Date: time.Now()}
+	body, _ := json.Marshal(post)
+	req := authedRequest("POST", "/posts", body, alice)
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.CreatePost)(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var resp struct {
+		Errors []FieldError `json:"errors"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "content" {
+		t.Errorf("unexpected error body: %+v", resp.Errors)
+	}
+}
+
+func TestCreatePost_RejectsClientSuppliedID(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	alice := registerTestUser(backend, "alice")
+	post := Post{ID: "client-chosen", Author: "alice", Content: "hello", Date: time.Now()}
+	body, _ := json.Marshal(post)
+	req := authedRequest("POST", "/posts", body, alice)
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.CreatePost)(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestUpdatePostById_ValidationError(t *testing.T) {
+	backend := setupBackend(NewMemoryStore())
+	alice := registerTestUser(backend, "alice")
+	backend.Store.Create(context.Background(), Post{ID: "1", Author: alice.ID, Content: "Old", Date: time.Now()})
+
+	updated := Post{Author: alice.ID, Content: "", Date: time.Now()}
+	body, _ := json.Marshal(updated)
+	req := authedRequest("PUT", "/posts/1", body, alice)
+	req.SetPathValue("post_id", "1")
+	w := httptest.NewRecorder()
+	backend.authenticate(backend.UpdatePostById)(w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+}